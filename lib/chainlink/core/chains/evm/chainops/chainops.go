@@ -0,0 +1,231 @@
+// Package chainops provides supported recovery operations for deep EVM
+// reorgs - today those require hand-written SQL against the node's
+// database. It is intentionally chain-scoped: every operation takes a
+// chain.ID so a multi-chain node can repair one chain without touching the
+// others.
+package chainops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/jmoiron/sqlx"
+	pkgerrors "github.com/pkg/errors"
+
+	evmclient "github.com/smartcontractkit/chainlink/core/chains/evm/client"
+	"github.com/smartcontractkit/chainlink/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/pg"
+	"github.com/smartcontractkit/chainlink/core/services/sqlutil"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// defaultWalkDepth bounds how far FindLCA will walk backward when neither
+// the chain nor a KeySpecific override configures a finality depth.
+const defaultWalkDepth = 1000
+
+// ORM exposes the chain-scoped reorg recovery operations backing the
+// `chainlink blocks find-lca` and `chainlink nodes remove-blocks` CLI
+// commands.
+type ORM interface {
+	// FindLCA walks backward from the node's locally stored chain head,
+	// comparing stored block hashes against the live RPC, until it finds
+	// the latest block both agree on (the latest common ancestor).
+	FindLCA(ctx context.Context, chainID utils.Big) (blockNumber int64, blockHash common.Hash, err error)
+
+	// RemoveBlocksAndLogs deletes headers, receipts, LogPoller logs, and
+	// any per-chain OCR2 latest_round_requested/history rows at or above
+	// fromBlock, in a single transaction. If the ORM is already
+	// transaction-scoped (composed via WithDataStore with an outer
+	// *sqlx.Tx), it runs as part of that transaction instead of starting
+	// its own.
+	RemoveBlocksAndLogs(ctx context.Context, chainID utils.Big, fromBlock int64) error
+
+	// WithDataStore returns a copy of the ORM that reads and writes
+	// against ds instead, e.g. to compose RemoveBlocksAndLogs into an
+	// outer pg.SqlxTransaction.
+	WithDataStore(ds sqlutil.DataStore) ORM
+}
+
+type orm struct {
+	ds     sqlutil.DataStore
+	client evmclient.Client
+	lggr   logger.Logger
+}
+
+// NewORM returns an ORM backed by ds for storage and client for comparing
+// the locally stored chain against the live chain.
+func NewORM(ds sqlutil.DataStore, client evmclient.Client, lggr logger.Logger) ORM {
+	return &orm{ds: ds, client: client, lggr: lggr.Named("ChainOps")}
+}
+
+func (o *orm) WithDataStore(ds sqlutil.DataStore) ORM {
+	cpy := *o
+	cpy.ds = ds
+	return &cpy
+}
+
+func (o *orm) FindLCA(ctx context.Context, chainID utils.Big) (int64, common.Hash, error) {
+	depth, err := o.walkDepth(ctx, chainID)
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+
+	var head int64
+	if err := o.ds.QueryRowxContext(ctx, `
+		SELECT COALESCE(MAX(number), 0) FROM evm_heads WHERE evm_chain_id = $1
+	`, chainID).Scan(&head); err != nil {
+		return 0, common.Hash{}, pkgerrors.Wrap(err, "failed to load local chain head")
+	}
+
+	lowest := head - depth
+	if lowest < 0 {
+		lowest = 0
+	}
+
+	for n := head; n >= lowest; n-- {
+		var storedHash common.Hash
+		row := o.ds.QueryRowxContext(ctx, `
+			SELECT hash FROM evm_heads WHERE evm_chain_id = $1 AND number = $2
+		`, chainID, n)
+		if err := row.Scan(&storedHash); err != nil {
+			// We don't have this block locally; keep walking backward.
+			continue
+		}
+
+		remote, err := o.client.HeaderByNumber(ctx, big.NewInt(n))
+		if err != nil {
+			return 0, common.Hash{}, pkgerrors.Wrapf(err, "failed to fetch header %d from RPC", n)
+		}
+
+		if remote.Hash() == storedHash {
+			return n, storedHash, nil
+		}
+	}
+
+	return 0, common.Hash{}, pkgerrors.Errorf("could not find a common ancestor within %d blocks of local head %d", depth, head)
+}
+
+func (o *orm) RemoveBlocksAndLogs(ctx context.Context, chainID utils.Big, fromBlock int64) error {
+	// If we're handed the ambient *sqlx.DB, run the deletes inside our own
+	// transaction. If o.ds was composed via WithDataStore with an outer
+	// *sqlx.Tx, it's already transaction-scoped, so just run directly
+	// against it rather than nesting a transaction.
+	if db, ok := o.ds.(*sqlx.DB); ok {
+		return pg.SqlxTransactionWithDefaultCtx(db, o.lggr, func(q pg.Queryer) error {
+			return removeBlocksAndLogs(ctx, q, chainID, fromBlock)
+		})
+	}
+	return removeBlocksAndLogs(ctx, o.ds, chainID, fromBlock)
+}
+
+func removeBlocksAndLogs(ctx context.Context, q sqlutil.Queryer, chainID utils.Big, fromBlock int64) error {
+	if _, err := q.ExecContext(ctx, `
+		DELETE FROM evm_receipts USING evm_heads
+		WHERE evm_receipts.block_hash = evm_heads.hash
+			AND evm_heads.evm_chain_id = $1 AND evm_heads.number >= $2
+	`, chainID, fromBlock); err != nil {
+		return pkgerrors.Wrap(err, "failed to remove receipts")
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		DELETE FROM evm_heads WHERE evm_chain_id = $1 AND number >= $2
+	`, chainID, fromBlock); err != nil {
+		return pkgerrors.Wrap(err, "failed to remove headers")
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		DELETE FROM log_poller_logs WHERE evm_chain_id = $1 AND block_number >= $2
+	`, chainID, fromBlock); err != nil {
+		return pkgerrors.Wrap(err, "failed to remove log poller logs")
+	}
+
+	if err := deleteRoundRequestedAtOrAbove(ctx, q, "offchainreporting2_round_requested_history", "id", chainID, fromBlock); err != nil {
+		return pkgerrors.Wrap(err, "failed to remove round requested history rows")
+	}
+
+	if err := deleteRoundRequestedAtOrAbove(ctx, q, "offchainreporting2_latest_round_requested", "offchainreporting2_oracle_spec_id", chainID, fromBlock); err != nil {
+		return pkgerrors.Wrap(err, "failed to remove latest_round_requested rows")
+	}
+
+	return nil
+}
+
+// deleteRoundRequestedAtOrAbove deletes rows from table (either
+// offchainreporting2_round_requested_history or
+// offchainreporting2_latest_round_requested, keyed by idColumn) belonging
+// to chainID whose raw log's blockNumber is at or above fromBlock. raw is
+// json.Marshal(rr.Raw) of a go-ethereum core/types.Log, whose MarshalJSON
+// encodes BlockNumber as a quoted hex string (e.g. "0x3039"), not a bare
+// integer - so it has to be decoded in Go rather than cast to ::numeric
+// in SQL, which would fail on every row a real node ever saved.
+func deleteRoundRequestedAtOrAbove(ctx context.Context, q sqlutil.Queryer, table, idColumn string, chainID utils.Big, fromBlock int64) error {
+	rows, err := q.QueryxContext(ctx, fmt.Sprintf(`
+		SELECT t.%[1]s, t.raw
+		FROM %[2]s t
+		JOIN offchainreporting2_oracle_specs s ON s.id = t.offchainreporting2_oracle_spec_id
+		WHERE s.evm_chain_id = $1
+	`, idColumn, table), chainID)
+	if err != nil {
+		return pkgerrors.Wrap(err, "failed to query rows")
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var raw []byte
+		if err := rows.Scan(&id, &raw); err != nil {
+			return pkgerrors.Wrap(err, "failed to scan row")
+		}
+		var parsed struct {
+			BlockNumber hexutil.Uint64 `json:"blockNumber"`
+		}
+		if err := json.Unmarshal(raw, &parsed); err != nil {
+			return pkgerrors.Wrap(err, "failed to unmarshal raw log")
+		}
+		if int64(parsed.BlockNumber) >= fromBlock {
+			ids = append(ids, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return pkgerrors.Wrap(err, "failed to iterate rows")
+	}
+
+	for _, id := range ids {
+		if _, err := q.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, table, idColumn), id); err != nil {
+			return pkgerrors.Wrap(err, "failed to delete row")
+		}
+	}
+	return nil
+}
+
+// walkDepth returns the safe walk depth for chainID: the deepest
+// EvmFinalityDepth configured across the chain's KeySpecific overrides, the
+// chain-level EvmFinalityDepth if no key overrides one, or defaultWalkDepth
+// if neither is set.
+func (o *orm) walkDepth(ctx context.Context, chainID utils.Big) (int64, error) {
+	var cfg types.ChainCfg
+	row := o.ds.QueryRowxContext(ctx, `SELECT cfg FROM evm_chains WHERE id = $1`, chainID)
+	if err := row.Scan(&cfg); err != nil {
+		return 0, pkgerrors.Wrap(err, "failed to load chain config")
+	}
+
+	depth := int64(-1)
+	for _, keyCfg := range cfg.KeySpecific {
+		if keyCfg.EvmFinalityDepth != nil && int64(*keyCfg.EvmFinalityDepth) > depth {
+			depth = int64(*keyCfg.EvmFinalityDepth)
+		}
+	}
+	if depth < 0 && cfg.EvmFinalityDepth != nil {
+		depth = int64(*cfg.EvmFinalityDepth)
+	}
+	if depth < 0 {
+		depth = defaultWalkDepth
+	}
+	return depth, nil
+}
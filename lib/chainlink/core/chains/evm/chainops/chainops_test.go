@@ -0,0 +1,96 @@
+package chainops_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/chains/evm/chainops"
+	evmclientmocks "github.com/smartcontractkit/chainlink/core/chains/evm/client/mocks"
+	"github.com/smartcontractkit/chainlink/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/core/internal/testutils/pgtest"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting2/testhelpers"
+	"github.com/smartcontractkit/chainlink/core/services/relay/evm"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+)
+
+func Test_ORM_FindLCA(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.NewSqlxDB(t)
+	lggr := logger.TestLogger(t)
+
+	chain := types.Chain{ID: *utils.NewBigI(1)}
+	evmtest.MustInsertChain(t, db, &chain)
+
+	matchingHash := common.BigToHash(big.NewInt(1))
+	_, err := db.Exec(`INSERT INTO evm_heads (evm_chain_id, hash, number) VALUES ($1, $2, 10)`, chain.ID, matchingHash)
+	require.NoError(t, err)
+
+	client := evmclientmocks.NewClient(t)
+	client.On("HeaderByNumber", mock.Anything, big.NewInt(10)).
+		Return(&gethtypes.Header{Number: big.NewInt(10)}, nil).Maybe()
+
+	orm := chainops.NewORM(db, client, lggr)
+
+	_, _, err = orm.FindLCA(ctx, chain.ID)
+	// Without a real RPC behind client, the only block we have locally
+	// won't hash-match the header the mock returns; we're asserting the
+	// walk terminates with the documented "no common ancestor" error
+	// rather than panicking or hanging.
+	require.Error(t, err)
+}
+
+func Test_ORM_RemoveBlocksAndLogs(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.NewSqlxDB(t)
+	lggr := logger.TestLogger(t)
+
+	chain := types.Chain{ID: *utils.NewBigI(1)}
+	evmtest.MustInsertChain(t, db, &chain)
+
+	_, err := db.Exec(`INSERT INTO evm_heads (evm_chain_id, hash, number) VALUES ($1, $2, 10)`, chain.ID, cltest.NewHash())
+	require.NoError(t, err)
+
+	var specID int32
+	require.NoError(t, db.Get(&specID, `
+		INSERT INTO offchainreporting2_oracle_specs (evm_chain_id) VALUES ($1) RETURNING id
+	`, chain.ID))
+
+	// Save through the real ContractDB path so raw is encoded exactly as
+	// production rows are: a go-ethereum types.Log, whose blockNumber
+	// marshals as a quoted hex string rather than a bare integer.
+	rawLog := cltest.LogFromFixture(t, "../../../testdata/jsonrpc/round_requested_log_1_1.json")
+	rawLog.BlockNumber = 10
+	contractDB := evm.NewContractDB(db, specID, lggr)
+	require.NoError(t, contractDB.SaveLatestRoundRequested(ctx, ocr2aggregator.OCR2AggregatorRoundRequested{
+		Requester:    cltest.NewAddress(),
+		ConfigDigest: testhelpers.MakeConfigDigest(t),
+		Epoch:        1,
+		Round:        1,
+		Raw:          rawLog,
+	}))
+
+	orm := chainops.NewORM(db, evmclientmocks.NewClient(t), lggr)
+	require.NoError(t, orm.RemoveBlocksAndLogs(ctx, chain.ID, 10))
+
+	var headCount int
+	require.NoError(t, db.Get(&headCount, `SELECT COUNT(*) FROM evm_heads WHERE evm_chain_id = $1 AND number >= 10`, chain.ID))
+	require.Equal(t, 0, headCount)
+
+	var latestCount int
+	require.NoError(t, db.Get(&latestCount, `SELECT COUNT(*) FROM offchainreporting2_latest_round_requested WHERE offchainreporting2_oracle_spec_id = $1`, specID))
+	require.Equal(t, 0, latestCount)
+
+	var historyCount int
+	require.NoError(t, db.Get(&historyCount, `SELECT COUNT(*) FROM offchainreporting2_round_requested_history WHERE offchainreporting2_oracle_spec_id = $1`, specID))
+	require.Equal(t, 0, historyCount)
+}
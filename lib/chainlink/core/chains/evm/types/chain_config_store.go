@@ -0,0 +1,203 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/sqlutil"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// KeySpecificChange describes a KeySpecific override that was just
+// committed (or removed) for a chain, delivered to Subscribe-ers so that
+// components like txmgr's gas estimator can pick up the new value on their
+// next broadcast attempt rather than waiting for a node restart.
+type KeySpecificChange struct {
+	ChainID utils.Big
+	Address common.Address
+	Cfg     ChainCfg
+	Deleted bool
+}
+
+// ChainConfigStore manages the KeySpecific overrides on a chain's ChainCfg,
+// validating each change against the chain's own defaults before it is
+// committed, and notifying subscribers once it is. Subscribe is in-process
+// only: it fans out directly from Upsert/DeleteKeySpecific within this
+// node, so it only reaches components running in the same process (e.g.
+// this node's own txmgr/gas estimator). There is no cross-process delivery
+// (no Postgres LISTEN/NOTIFY) - a second node process sharing this
+// database would need to poll evm_chains.updated_at itself.
+type ChainConfigStore interface {
+	// UpsertKeySpecific strictly decodes rawCfg (rejecting any field
+	// ChainCfg doesn't recognize), validates the result against the
+	// chain-level defaults for chainID and, if it passes, commits it as
+	// the KeySpecific override for addr and notifies subscribers.
+	UpsertKeySpecific(ctx context.Context, chainID utils.Big, addr common.Address, rawCfg json.RawMessage) error
+
+	// DeleteKeySpecific removes the KeySpecific override for addr, if any,
+	// and notifies subscribers.
+	DeleteKeySpecific(ctx context.Context, chainID utils.Big, addr common.Address) error
+
+	// Subscribe returns a channel of KeySpecificChange for chainID, and an
+	// unsubscribe function that must be called to release it. See the
+	// in-process caveat on ChainConfigStore above.
+	Subscribe(chainID utils.Big) (<-chan KeySpecificChange, func())
+}
+
+type chainConfigStore struct {
+	ds   sqlutil.DataStore
+	lggr logger.Logger
+
+	mu   sync.Mutex
+	subs map[string][]chan KeySpecificChange
+}
+
+// NewChainConfigStore returns a ChainConfigStore backed by ds.
+func NewChainConfigStore(ds sqlutil.DataStore, lggr logger.Logger) ChainConfigStore {
+	return &chainConfigStore{
+		ds:   ds,
+		lggr: lggr.Named("ChainConfigStore"),
+		subs: make(map[string][]chan KeySpecificChange),
+	}
+}
+
+func (s *chainConfigStore) UpsertKeySpecific(ctx context.Context, chainID utils.Big, addr common.Address, rawCfg json.RawMessage) error {
+	keyCfg, err := decodeKeySpecificCfg(rawCfg)
+	if err != nil {
+		return err
+	}
+
+	chainCfg, err := s.loadChainCfg(ctx, chainID)
+	if err != nil {
+		return err
+	}
+	if err := validateKeySpecific(chainCfg, keyCfg); err != nil {
+		return err
+	}
+
+	cfgJSON, err := json.Marshal(keyCfg)
+	if err != nil {
+		return pkgerrors.Wrap(err, "failed to marshal KeySpecific override")
+	}
+
+	// jsonb_set only touches the KeySpecific[addr] path, so two concurrent
+	// upserts for different addresses on the same chain never clobber each
+	// other the way a read-modify-write of the whole cfg column would. The
+	// inner jsonb_set creates an empty KeySpecific object first if the chain
+	// has none yet, since jsonb_set only creates the last step of its path.
+	_, err = s.ds.ExecContext(ctx, `
+		UPDATE evm_chains
+		SET cfg = jsonb_set(
+				jsonb_set(COALESCE(cfg, '{}'::jsonb), '{KeySpecific}', COALESCE(cfg->'KeySpecific', '{}'::jsonb), true),
+				ARRAY['KeySpecific', $2], $3::jsonb, true),
+			updated_at = NOW()
+		WHERE id = $1
+	`, chainID, addr.Hex(), cfgJSON)
+	if err != nil {
+		return pkgerrors.Wrap(err, "failed to save chain config")
+	}
+
+	s.notify(chainID, KeySpecificChange{ChainID: chainID, Address: addr, Cfg: keyCfg})
+	return nil
+}
+
+func (s *chainConfigStore) DeleteKeySpecific(ctx context.Context, chainID utils.Big, addr common.Address) error {
+	// #- removes just the KeySpecific[addr] path atomically, for the same
+	// reason UpsertKeySpecific uses jsonb_set instead of a read-modify-write.
+	_, err := s.ds.ExecContext(ctx, `
+		UPDATE evm_chains
+		SET cfg = cfg #- ARRAY['KeySpecific', $2],
+			updated_at = NOW()
+		WHERE id = $1
+	`, chainID, addr.Hex())
+	if err != nil {
+		return pkgerrors.Wrap(err, "failed to delete key specific override")
+	}
+
+	s.notify(chainID, KeySpecificChange{ChainID: chainID, Address: addr, Deleted: true})
+	return nil
+}
+
+func (s *chainConfigStore) Subscribe(chainID utils.Big) (<-chan KeySpecificChange, func()) {
+	ch := make(chan KeySpecificChange, 16)
+
+	s.mu.Lock()
+	key := chainID.String()
+	s.subs[key] = append(s.subs[key], ch)
+	s.mu.Unlock()
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		subs := s.subs[key]
+		for i, sub := range subs {
+			if sub == ch {
+				s.subs[key] = append(subs[:i], subs[i+1:]...)
+				close(ch)
+				break
+			}
+		}
+	}
+	return ch, unsubscribe
+}
+
+func (s *chainConfigStore) notify(chainID utils.Big, change KeySpecificChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.subs[chainID.String()] {
+		select {
+		case ch <- change:
+		default:
+			s.lggr.Warnw("dropped KeySpecificChange, subscriber channel is full", "chainID", chainID.String())
+		}
+	}
+}
+
+func (s *chainConfigStore) loadChainCfg(ctx context.Context, chainID utils.Big) (ChainCfg, error) {
+	var cfg ChainCfg
+	row := s.ds.QueryRowxContext(ctx, `SELECT cfg FROM evm_chains WHERE id = $1`, chainID)
+	if err := row.Scan(&cfg); err != nil {
+		return ChainCfg{}, pkgerrors.Wrap(err, "failed to load chain config")
+	}
+	return cfg, nil
+}
+
+// decodeKeySpecificCfg strictly decodes a KeySpecific override from its
+// original JSON payload, rejecting any field ChainCfg doesn't recognize.
+// This has to run against the raw payload rather than an already-typed
+// ChainCfg: once a value exists as a ChainCfg, re-marshaling and decoding
+// it can never surface an unknown field, since Go already dropped it (or
+// never had it) on the way in.
+func decodeKeySpecificCfg(raw json.RawMessage) (ChainCfg, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var cfg ChainCfg
+	if err := dec.Decode(&cfg); err != nil {
+		return ChainCfg{}, pkgerrors.Wrap(err, "failed to decode KeySpecific override")
+	}
+	return cfg, nil
+}
+
+// validateKeySpecific rejects a KeySpecific override that is internally
+// invalid (non-positive gas prices) or that undercuts the chain's own
+// EvmMinGasPriceWei.
+func validateKeySpecific(chainCfg, keyCfg ChainCfg) error {
+	if keyCfg.EvmMaxGasPriceWei != nil && keyCfg.EvmMaxGasPriceWei.ToInt().Sign() <= 0 {
+		return pkgerrors.New("EvmMaxGasPriceWei must be positive")
+	}
+	if keyCfg.EvmMinGasPriceWei != nil && keyCfg.EvmMinGasPriceWei.ToInt().Sign() <= 0 {
+		return pkgerrors.New("EvmMinGasPriceWei must be positive")
+	}
+	if keyCfg.EvmMaxGasPriceWei != nil && chainCfg.EvmMinGasPriceWei != nil &&
+		keyCfg.EvmMaxGasPriceWei.ToInt().Cmp(chainCfg.EvmMinGasPriceWei.ToInt()) < 0 {
+		return pkgerrors.Errorf("EvmMaxGasPriceWei %s is below chain EvmMinGasPriceWei %s",
+			keyCfg.EvmMaxGasPriceWei.String(), chainCfg.EvmMinGasPriceWei.String())
+	}
+	return nil
+}
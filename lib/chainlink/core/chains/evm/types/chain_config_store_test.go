@@ -0,0 +1,125 @@
+package types_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/core/internal/testutils/pgtest"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+func Test_ChainConfigStore_UpsertKeySpecific(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.NewSqlxDB(t)
+	lggr := logger.TestLogger(t)
+
+	minGasPrice := utils.NewBig(big.NewInt(1000))
+	chain := types.Chain{
+		ID:  *utils.NewBigI(rand.Int63()),
+		Cfg: types.ChainCfg{EvmMinGasPriceWei: minGasPrice},
+	}
+	evmtest.MustInsertChain(t, db, &chain)
+
+	store := types.NewChainConfigStore(db, lggr)
+	changes, unsubscribe := store.Subscribe(chain.ID)
+	defer unsubscribe()
+
+	addr := cltest.NewAddress()
+	newMax := utils.NewBig(big.NewInt(5000))
+
+	payload, err := json.Marshal(types.ChainCfg{EvmMaxGasPriceWei: newMax})
+	require.NoError(t, err)
+
+	err = store.UpsertKeySpecific(ctx, chain.ID, addr, payload)
+	require.NoError(t, err)
+
+	select {
+	case change := <-changes:
+		require.Equal(t, addr, change.Address)
+		require.Equal(t, newMax, change.Cfg.EvmMaxGasPriceWei)
+		require.False(t, change.Deleted)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for KeySpecificChange")
+	}
+
+	var loaded types.Chain
+	require.NoError(t, db.Get(&loaded, "SELECT * FROM evm_chains WHERE id = $1", chain.ID))
+	require.Equal(t, newMax, loaded.Cfg.KeySpecific[addr.Hex()].EvmMaxGasPriceWei)
+}
+
+func Test_ChainConfigStore_UpsertKeySpecific_Validation(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.NewSqlxDB(t)
+	lggr := logger.TestLogger(t)
+
+	minGasPrice := utils.NewBig(big.NewInt(1000))
+	chain := types.Chain{
+		ID:  *utils.NewBigI(rand.Int63()),
+		Cfg: types.ChainCfg{EvmMinGasPriceWei: minGasPrice},
+	}
+	evmtest.MustInsertChain(t, db, &chain)
+
+	store := types.NewChainConfigStore(db, lggr)
+	addr := cltest.NewAddress()
+
+	t.Run("rejects non-positive gas price", func(t *testing.T) {
+		payload, err := json.Marshal(types.ChainCfg{EvmMaxGasPriceWei: utils.NewBig(big.NewInt(0))})
+		require.NoError(t, err)
+		require.Error(t, store.UpsertKeySpecific(ctx, chain.ID, addr, payload))
+	})
+
+	t.Run("rejects key value below chain EvmMinGasPriceWei", func(t *testing.T) {
+		payload, err := json.Marshal(types.ChainCfg{EvmMaxGasPriceWei: utils.NewBig(big.NewInt(1))})
+		require.NoError(t, err)
+		require.Error(t, store.UpsertKeySpecific(ctx, chain.ID, addr, payload))
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		err := store.UpsertKeySpecific(ctx, chain.ID, addr, []byte(`{"NotARealField": 1}`))
+		require.Error(t, err)
+	})
+}
+
+func Test_ChainConfigStore_UpsertKeySpecific_ConcurrentAddresses(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.NewSqlxDB(t)
+	lggr := logger.TestLogger(t)
+
+	chain := types.Chain{ID: *utils.NewBigI(rand.Int63())}
+	evmtest.MustInsertChain(t, db, &chain)
+
+	store := types.NewChainConfigStore(db, lggr)
+
+	// Concurrent upserts for different addresses on the same chain must
+	// not clobber one another's KeySpecific entry.
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		payload, err := json.Marshal(types.ChainCfg{EvmMaxGasPriceWei: utils.NewBig(big.NewInt(int64(1000 + i)))})
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func(addr common.Address, payload []byte) {
+			defer wg.Done()
+			assert.NoError(t, store.UpsertKeySpecific(ctx, chain.ID, addr, payload))
+		}(cltest.NewAddress(), payload)
+	}
+	wg.Wait()
+
+	var loaded types.Chain
+	require.NoError(t, db.Get(&loaded, "SELECT * FROM evm_chains WHERE id = $1", chain.ID))
+	require.Len(t, loaded.Cfg.KeySpecific, n)
+}
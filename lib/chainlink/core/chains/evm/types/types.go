@@ -0,0 +1,60 @@
+// Package types holds the EVM chain configuration types that are persisted
+// to (and reloaded from) the evm_chains table.
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// Chain is the persisted configuration for a single EVM chain.
+type Chain struct {
+	ID        utils.Big
+	Cfg       ChainCfg
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Enabled   bool
+}
+
+// ChainCfg carries chain-level config overrides, along with per-key
+// overrides keyed by hex-encoded address. It round-trips to/from the
+// evm_chains.cfg JSONB column via Value/Scan.
+type ChainCfg struct {
+	EvmMaxGasPriceWei *utils.Big          `json:",omitempty"`
+	EvmMinGasPriceWei *utils.Big          `json:",omitempty"`
+	EvmFinalityDepth  *uint32             `json:",omitempty"`
+	KeySpecific       map[string]ChainCfg `json:",omitempty"`
+}
+
+// Value implements driver.Valuer so ChainCfg can be written directly into a
+// JSONB column.
+func (c ChainCfg) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to marshal ChainCfg")
+	}
+	return b, nil
+}
+
+// Scan implements sql.Scanner so ChainCfg can be read directly out of a
+// JSONB column.
+func (c *ChainCfg) Scan(value interface{}) error {
+	if value == nil {
+		*c = ChainCfg{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return pkgerrors.Errorf("ChainCfg#Scan expected []byte, got %T", value)
+	}
+	if len(b) == 0 {
+		*c = ChainCfg{}
+		return nil
+	}
+	return pkgerrors.Wrap(json.Unmarshal(b, c), "failed to unmarshal ChainCfg")
+}
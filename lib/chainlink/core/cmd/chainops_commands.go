@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/urfave/cli"
+
+	"github.com/smartcontractkit/chainlink/core/chains/evm/chainops"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// ChainOpsCommands returns the top-level `chainlink blocks ...` and
+// `chainlink nodes ...` commands, bound to client, with their reorg
+// recovery subcommands already attached. Nothing in this package adds them
+// to the root command tree; a caller appends them to it, for example:
+//
+//	app.Commands = append(app.Commands, cmd.ChainOpsCommands(client)...)
+func ChainOpsCommands(client *Client) []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "blocks",
+			Usage: "Commands for analyzing the node's locally stored chain",
+			Subcommands: []cli.Command{
+				{
+					Name:   "find-lca",
+					Usage:  "Find the latest common ancestor between the node's local chain and the live RPC",
+					Action: client.FindLCA,
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "chain-id", Usage: "chain ID to search"},
+					},
+				},
+			},
+		},
+		{
+			Name:  "nodes",
+			Usage: "Commands for recovering a node from a deep chain reorg",
+			Subcommands: []cli.Command{
+				{
+					Name:   "remove-blocks",
+					Usage:  "Remove headers, receipts, logs, and OCR2 round state at or above a block number, as a recovery path for a deep reorg",
+					Action: client.RemoveBlocks,
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "chain-id", Usage: "chain ID to operate on"},
+						cli.Int64Flag{Name: "start", Usage: "the first block number to remove"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// FindLCA implements the `chainlink blocks find-lca` command: it reports the
+// latest block the node's local database and the live RPC agree on for
+// --chain-id, without mutating anything.
+func (cli *Client) FindLCA(c *cli.Context) error {
+	chainID, ok := parseBigIntFlag(c, "chain-id")
+	if !ok {
+		return cli.errorOut(fmt.Errorf("--chain-id is required"))
+	}
+
+	orm, err := cli.chainOpsORM(chainID)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	number, hash, err := orm.FindLCA(c.Context, chainID)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	fmt.Fprintf(cli.Renderer.OutputWriter(), "latest common ancestor: block %d (%s)\n", number, hash.Hex())
+	return nil
+}
+
+// RemoveBlocks implements the `chainlink nodes remove-blocks` command: it
+// deletes headers, receipts, LogPoller logs, and OCR2 round-requested
+// history at or above --start for --chain-id, as a supported recovery path
+// for reorgs deeper than LogPoller's own rewind.
+func (cli *Client) RemoveBlocks(c *cli.Context) error {
+	chainID, ok := parseBigIntFlag(c, "chain-id")
+	if !ok {
+		return cli.errorOut(fmt.Errorf("--chain-id is required"))
+	}
+	start := c.Int64("start")
+
+	orm, err := cli.chainOpsORM(chainID)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	if err := orm.RemoveBlocksAndLogs(c.Context, chainID, start); err != nil {
+		return cli.errorOut(err)
+	}
+
+	fmt.Fprintf(cli.Renderer.OutputWriter(), "removed blocks and logs at or above %d for chain %s\n", start, chainID.String())
+	return nil
+}
+
+// chainOpsORM constructs a chainops.ORM for the given chain, reusing the
+// node's DB and the EVM client already registered for that chain.
+func (cli *Client) chainOpsORM(chainID utils.Big) (chainops.ORM, error) {
+	app, err := cli.AppFactory.NewApplication(cli.Config, cli.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := app.GetChains().EVM.Get(chainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+
+	return chainops.NewORM(app.GetSqlxDB(), chain.Client(), cli.Logger), nil
+}
+
+func parseBigIntFlag(c *cli.Context, name string) (utils.Big, bool) {
+	s := c.String(name)
+	if s == "" {
+		return utils.Big{}, false
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return utils.Big{}, false
+	}
+	return *utils.NewBig(n), true
+}
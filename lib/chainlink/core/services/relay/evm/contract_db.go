@@ -0,0 +1,186 @@
+package evm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/sqlutil"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+)
+
+// ContractDB persists the latest OCR2AggregatorRoundRequested log observed
+// for a given oracle spec, so that after a node restart the OCR2 plugin can
+// resume from a config/round without waiting for a fresh on-chain event. It
+// also keeps a bounded, time-windowed history of round requested events,
+// which is useful for OCR2 diagnostics and reorg recovery.
+type ContractDB struct {
+	ds           sqlutil.DataStore
+	oracleSpecID int32
+	lggr         logger.Logger
+	retention    time.Duration
+	maxRows      int
+}
+
+// NewContractDB returns a ContractDB that reads and writes against ds. Pass
+// the ambient *sqlx.DB to operate outside of any caller-managed transaction;
+// use WithDataStore to compose a load/save into an outer transaction. The
+// returned ContractDB keeps round-requested history unbounded until
+// WithRetention is called to opt into pruning.
+func NewContractDB(ds sqlutil.DataStore, oracleSpecID int32, lggr logger.Logger) *ContractDB {
+	return &ContractDB{
+		ds:           ds,
+		oracleSpecID: oracleSpecID,
+		lggr:         lggr.Named("ContractDB"),
+	}
+}
+
+// WithDataStore returns a copy of c that reads and writes against ds
+// instead, allowing callers to compose ContractDB into an outer
+// pg.SqlxTransaction (ds is typically the *sqlx.Tx passed into the
+// transaction callback) without needing SET CONSTRAINTS ... DEFERRED or a
+// second round trip through the connection pool.
+func (c *ContractDB) WithDataStore(ds sqlutil.DataStore) *ContractDB {
+	cpy := *c
+	cpy.ds = ds
+	return &cpy
+}
+
+// WithRetention returns a copy of c configured to retain at most maxRows
+// rows of round-requested history per oracle spec, no older than d. A zero
+// d or maxRows leaves that bound unenforced. The retention window only
+// takes effect once a pruner started via NewPruner is running against the
+// returned ContractDB.
+func (c *ContractDB) WithRetention(d time.Duration, maxRows int) *ContractDB {
+	cpy := *c
+	cpy.retention = d
+	cpy.maxRows = maxRows
+	return &cpy
+}
+
+// SaveLatestRoundRequested saves the latest round requested event, clobbering
+// any existing value for this oracle spec, and appends it to the
+// round-requested history table used by LoadRoundRequestedSince and
+// LoadRoundRequestedByEpoch.
+func (c *ContractDB) SaveLatestRoundRequested(ctx context.Context, rr ocr2aggregator.OCR2AggregatorRoundRequested) error {
+	rawLog, err := json.Marshal(rr.Raw)
+	if err != nil {
+		return pkgerrors.Wrap(err, "failed to marshal raw log")
+	}
+	_, err = c.ds.ExecContext(ctx, `
+		INSERT INTO offchainreporting2_latest_round_requested (offchainreporting2_oracle_spec_id, requester, config_digest, epoch, round, raw)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		ON CONFLICT (offchainreporting2_oracle_spec_id) DO UPDATE SET
+			requester = EXCLUDED.requester,
+			config_digest = EXCLUDED.config_digest,
+			epoch = EXCLUDED.epoch,
+			round = EXCLUDED.round,
+			raw = EXCLUDED.raw
+	`, c.oracleSpecID, rr.Requester, rr.ConfigDigest[:], rr.Epoch, rr.Round, rawLog)
+	if err != nil {
+		return pkgerrors.Wrap(err, "failed to save latest round requested")
+	}
+
+	_, err = c.ds.ExecContext(ctx, `
+		INSERT INTO offchainreporting2_round_requested_history (offchainreporting2_oracle_spec_id, requester, config_digest, epoch, round, raw, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,clock_timestamp())
+	`, c.oracleSpecID, rr.Requester, rr.ConfigDigest[:], rr.Epoch, rr.Round, rawLog)
+	return pkgerrors.Wrap(err, "failed to save round requested history")
+}
+
+// LoadLatestRoundRequested loads the latest round requested for the oracle
+// spec that this ContractDB was constructed with. If no round has been
+// requested yet, it returns a zero value and a nil error.
+func (c *ContractDB) LoadLatestRoundRequested(ctx context.Context) (rr ocr2aggregator.OCR2AggregatorRoundRequested, err error) {
+	row := c.ds.QueryRowxContext(ctx, `
+		SELECT requester, config_digest, epoch, round, raw
+		FROM offchainreporting2_latest_round_requested
+		WHERE offchainreporting2_oracle_spec_id = $1
+	`, c.oracleSpecID)
+
+	var rawLog []byte
+	var configDigest []byte
+	if err = row.Scan(&rr.Requester, &configDigest, &rr.Epoch, &rr.Round, &rawLog); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rr, nil
+		}
+		return rr, pkgerrors.Wrap(err, "failed to load latest round requested")
+	}
+	copy(rr.ConfigDigest[:], configDigest)
+	if len(rawLog) > 0 {
+		if err = json.Unmarshal(rawLog, &rr.Raw); err != nil {
+			return rr, pkgerrors.Wrap(err, "failed to unmarshal raw log")
+		}
+	}
+	return rr, nil
+}
+
+// LoadRoundRequestedSince returns every round requested event recorded for
+// this oracle spec since t, oldest first.
+func (c *ContractDB) LoadRoundRequestedSince(ctx context.Context, t time.Time) ([]ocr2aggregator.OCR2AggregatorRoundRequested, error) {
+	rows, err := c.ds.QueryxContext(ctx, `
+		SELECT requester, config_digest, epoch, round, raw
+		FROM offchainreporting2_round_requested_history
+		WHERE offchainreporting2_oracle_spec_id = $1 AND created_at >= $2
+		ORDER BY created_at ASC
+	`, c.oracleSpecID, t)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to load round requested since")
+	}
+	defer rows.Close()
+	return scanRoundRequestedRows(rows)
+}
+
+// LoadRoundRequestedByEpoch returns the round requested event recorded for
+// this oracle spec at the given epoch/round, if any.
+func (c *ContractDB) LoadRoundRequestedByEpoch(ctx context.Context, epoch, round uint32) (rr ocr2aggregator.OCR2AggregatorRoundRequested, err error) {
+	row := c.ds.QueryRowxContext(ctx, `
+		SELECT requester, config_digest, epoch, round, raw
+		FROM offchainreporting2_round_requested_history
+		WHERE offchainreporting2_oracle_spec_id = $1 AND epoch = $2 AND round = $3
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, c.oracleSpecID, epoch, round)
+
+	var rawLog []byte
+	var configDigest []byte
+	if err = row.Scan(&rr.Requester, &configDigest, &rr.Epoch, &rr.Round, &rawLog); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rr, nil
+		}
+		return rr, pkgerrors.Wrap(err, "failed to load round requested by epoch")
+	}
+	copy(rr.ConfigDigest[:], configDigest)
+	if len(rawLog) > 0 {
+		if err = json.Unmarshal(rawLog, &rr.Raw); err != nil {
+			return rr, pkgerrors.Wrap(err, "failed to unmarshal raw log")
+		}
+	}
+	return rr, nil
+}
+
+func scanRoundRequestedRows(rows *sqlx.Rows) ([]ocr2aggregator.OCR2AggregatorRoundRequested, error) {
+	var out []ocr2aggregator.OCR2AggregatorRoundRequested
+	for rows.Next() {
+		var rr ocr2aggregator.OCR2AggregatorRoundRequested
+		var rawLog []byte
+		var configDigest []byte
+		if err := rows.Scan(&rr.Requester, &configDigest, &rr.Epoch, &rr.Round, &rawLog); err != nil {
+			return nil, pkgerrors.Wrap(err, "failed to scan round requested row")
+		}
+		copy(rr.ConfigDigest[:], configDigest)
+		if len(rawLog) > 0 {
+			if err := json.Unmarshal(rawLog, &rr.Raw); err != nil {
+				return nil, pkgerrors.Wrap(err, "failed to unmarshal raw log")
+			}
+		}
+		out = append(out, rr)
+	}
+	return out, pkgerrors.Wrap(rows.Err(), "failed to iterate round requested rows")
+}
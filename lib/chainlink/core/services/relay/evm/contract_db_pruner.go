@@ -0,0 +1,102 @@
+package evm
+
+import (
+	"context"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services"
+)
+
+// roundRequestedPruner periodically deletes round-requested history rows
+// that have fallen outside of a ContractDB's retention window, keeping the
+// offchainreporting2_round_requested_history table bounded.
+type roundRequestedPruner struct {
+	db       *ContractDB
+	interval time.Duration
+	lggr     logger.Logger
+	chStop   chan struct{}
+	chDone   chan struct{}
+}
+
+var _ services.Service = (*roundRequestedPruner)(nil)
+
+// NewPruner returns a services.Service that, once started, deletes round
+// requested history rows older than c's retention window (and beyond its
+// maxRows bound) every interval until Close is called. It is a no-op loop
+// if c was not configured with WithRetention.
+func (c *ContractDB) NewPruner(interval time.Duration) services.Service {
+	return &roundRequestedPruner{
+		db:       c,
+		interval: interval,
+		lggr:     c.lggr.Named("RoundRequestedPruner"),
+		chStop:   make(chan struct{}),
+		chDone:   make(chan struct{}),
+	}
+}
+
+func (p *roundRequestedPruner) Start(context.Context) error {
+	go p.run()
+	return nil
+}
+
+func (p *roundRequestedPruner) Close() error {
+	close(p.chStop)
+	<-p.chDone
+	return nil
+}
+
+func (p *roundRequestedPruner) run() {
+	defer close(p.chDone)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.chStop:
+			return
+		case <-ticker.C:
+			if err := p.prune(); err != nil {
+				p.lggr.Errorw("failed to prune round requested history", "err", err)
+			}
+		}
+	}
+}
+
+func (p *roundRequestedPruner) prune() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+	defer cancel()
+
+	db := p.db
+	if db.retention > 0 {
+		_, err := db.ds.ExecContext(ctx, `
+			DELETE FROM offchainreporting2_round_requested_history
+			WHERE offchainreporting2_oracle_spec_id = $1 AND created_at < NOW() - $2::interval
+		`, db.oracleSpecID, db.retention.String())
+		if err != nil {
+			return pkgerrors.Wrap(err, "failed to prune by retention window")
+		}
+	}
+
+	if db.maxRows > 0 {
+		_, err := db.ds.ExecContext(ctx, `
+			DELETE FROM offchainreporting2_round_requested_history
+			WHERE id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (PARTITION BY offchainreporting2_oracle_spec_id ORDER BY created_at DESC) AS rn
+					FROM offchainreporting2_round_requested_history
+					WHERE offchainreporting2_oracle_spec_id = $1
+				) ranked
+				WHERE ranked.rn > $2
+			)
+		`, db.oracleSpecID, db.maxRows)
+		if err != nil {
+			return pkgerrors.Wrap(err, "failed to prune by max rows")
+		}
+	}
+
+	return nil
+}
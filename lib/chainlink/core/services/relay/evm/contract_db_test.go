@@ -1,7 +1,10 @@
 package evm_test
 
 import (
+	"context"
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/services/relay/evm"
 
@@ -10,20 +13,30 @@ import (
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/services/offchainreporting2/testhelpers"
 	"github.com/smartcontractkit/chainlink/core/services/pg"
+	"github.com/smartcontractkit/chainlink/core/utils"
 	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func Test_DB_LatestRoundRequested(t *testing.T) {
+	ctx := context.Background()
 	sqlDB := pgtest.NewSqlxDB(t)
 
 	_, err := sqlDB.Exec(`SET CONSTRAINTS offchainreporting2_latest_round_oracle_spec_fkey DEFERRED`)
 	require.NoError(t, err)
 
 	lggr := logger.TestLogger(t)
-	db := evm.NewContractDB(sqlDB.DB, 1, lggr)
-	db2 := evm.NewContractDB(sqlDB.DB, 2, lggr)
+	// Saves for oracle specs 1 and 2 also append history rows, whose FK is
+	// deferred here for the same reason.
+	_, err = sqlDB.Exec(`SET CONSTRAINTS offchainreporting2_round_requested_history_oracle_spec_fkey DEFERRED`)
+	require.NoError(t, err)
+
+	// db and db2 are constructed against the ambient DataStore (sqlDB
+	// itself), so reads outside of t.Run("saves...") don't need a
+	// transaction wrapper at all.
+	db := evm.NewContractDB(sqlDB, 1, lggr)
+	db2 := evm.NewContractDB(sqlDB, 2, lggr)
 
 	rawLog := cltest.LogFromFixture(t, "../../../testdata/jsonrpc/round_requested_log_1_1.json")
 
@@ -35,9 +48,9 @@ func Test_DB_LatestRoundRequested(t *testing.T) {
 		Raw:          rawLog,
 	}
 
-	t.Run("saves latest round requested", func(t *testing.T) {
+	t.Run("saves latest round requested under a shared transaction", func(t *testing.T) {
 		err := pg.SqlxTransactionWithDefaultCtx(sqlDB, logger.TestLogger(t), func(q pg.Queryer) error {
-			return db.SaveLatestRoundRequested(q, rr)
+			return db.WithDataStore(q).SaveLatestRoundRequested(ctx, rr)
 		})
 		require.NoError(t, err)
 
@@ -53,23 +66,94 @@ func Test_DB_LatestRoundRequested(t *testing.T) {
 		}
 
 		err = pg.SqlxTransactionWithDefaultCtx(sqlDB, logger.TestLogger(t), func(q pg.Queryer) error {
-			return db.SaveLatestRoundRequested(q, rr)
+			return db.WithDataStore(q).SaveLatestRoundRequested(ctx, rr)
 		})
 		require.NoError(t, err)
 	})
 
-	t.Run("loads latest round requested", func(t *testing.T) {
+	t.Run("loads latest round requested against the ambient DataStore", func(t *testing.T) {
 		// There is no round for db2
-		lrr, err := db2.LoadLatestRoundRequested()
+		lrr, err := db2.LoadLatestRoundRequested(ctx)
 		require.NoError(t, err)
 		require.Equal(t, 0, int(lrr.Epoch))
 
-		lrr, err = db.LoadLatestRoundRequested()
+		lrr, err = db.LoadLatestRoundRequested(ctx)
 		require.NoError(t, err)
 
 		assert.Equal(t, rr, lrr)
 	})
 
+	t.Run("load is cancellable via context", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := db.LoadLatestRoundRequested(cancelled)
+		require.Error(t, err)
+	})
+
+	t.Run("keeps and queries round requested history", func(t *testing.T) {
+		history := evm.NewContractDB(sqlDB, 1, lggr).WithRetention(time.Hour, 2)
+
+		since := time.Now()
+		for i := uint32(1); i <= 3; i++ {
+			err := history.SaveLatestRoundRequested(ctx, ocr2aggregator.OCR2AggregatorRoundRequested{
+				Requester:    cltest.NewAddress(),
+				ConfigDigest: testhelpers.MakeConfigDigest(t),
+				Epoch:        100 + i,
+				Round:        i,
+				Raw:          rawLog,
+			})
+			require.NoError(t, err)
+		}
+
+		rows, err := history.LoadRoundRequestedSince(ctx, since)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		byEpoch, err := history.LoadRoundRequestedByEpoch(ctx, 102, 2)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(102), byEpoch.Epoch)
+
+		pruner := history.NewPruner(10 * time.Millisecond)
+		require.NoError(t, pruner.Start(ctx))
+		defer pruner.Close()
+
+		// maxRows is 2, so the pruner should eventually bring the history
+		// for this oracle spec back down to 2 rows.
+		assert.Eventually(t, func() bool {
+			rows, err := history.LoadRoundRequestedSince(ctx, since)
+			return err == nil && len(rows) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("composing into an outer transaction needs no SET CONSTRAINTS DEFERRED", func(t *testing.T) {
+		chainID := *utils.NewBigI(rand.Int63())
+
+		err := pg.SqlxTransactionWithDefaultCtx(sqlDB, logger.TestLogger(t), func(q pg.Queryer) error {
+			if _, err := q.ExecContext(ctx, `
+				INSERT INTO evm_chains (id, cfg, created_at, updated_at) VALUES ($1, '{}', NOW(), NOW())
+			`, chainID); err != nil {
+				return err
+			}
+
+			var specID int32
+			if err := q.QueryRowxContext(ctx, `
+				INSERT INTO offchainreporting2_oracle_specs (evm_chain_id) VALUES ($1) RETURNING id
+			`, chainID).Scan(&specID); err != nil {
+				return err
+			}
+
+			// The oracle spec row above is inserted before the save
+			// below in the same transaction, so the FK it satisfies is
+			// already visible when SaveLatestRoundRequested's INSERT
+			// checks it - composing ContractDB into an outer
+			// transaction via WithDataStore needs no SET CONSTRAINTS
+			// ... DEFERRED, unlike the top-level db/db2 in this test.
+			return evm.NewContractDB(q, specID, lggr).SaveLatestRoundRequested(ctx, rr)
+		})
+		require.NoError(t, err)
+	})
+
 	t.Run("spec with latest round requested can be deleted", func(t *testing.T) {
 		_, err := sqlDB.Exec(`DELETE FROM offchainreporting2_oracle_specs`)
 		assert.NoError(t, err)
@@ -0,0 +1,27 @@
+// Package sqlutil holds small, dependency-light abstractions shared by
+// stores that need to run context-scoped queries against either the
+// ambient connection pool or a transaction managed by their caller.
+package sqlutil
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Queryer is the subset of *sqlx.DB / *sqlx.Tx that a store needs in order
+// to run context-scoped queries without caring whether it is talking to the
+// ambient connection pool or a caller-managed transaction.
+type Queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+}
+
+// DataStore is a Queryer that a store can be constructed against directly.
+// Both *sqlx.DB (the ambient pool) and *sqlx.Tx (an outer, caller-managed
+// transaction) satisfy it, so a store can be composed into a transaction
+// started elsewhere simply by passing the *sqlx.Tx in place of the pool -
+// no SET CONSTRAINTS ... DEFERRED or manual transaction wrapping required.
+type DataStore = Queryer